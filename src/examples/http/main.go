@@ -0,0 +1,30 @@
+// Command http demonstrates end-to-end correlation id propagation:
+// RequestIDMiddleware assigns or echoes X-Request-ID, and every log line
+// emitted while handling a request carries it as a correlation_id field.
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Caracal-IT/hydra/logger"
+)
+
+func main() {
+	logger.MustSetup("")
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = logger.Close(ctx)
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		logger.WithContext(r.Context()).Infof("handling /hello")
+		_, _ = w.Write([]byte("hello\n"))
+	})
+
+	logger.Log.Infof("listening on :8080")
+	_ = http.ListenAndServe(":8080", logger.RequestIDMiddleware(mux))
+}
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config represents logger configuration loaded from a file.
+type Config struct {
+	Level         string `yaml:"level" json:"level"`
+	ConsoleFormat string `yaml:"console_format" json:"console_format"` // "text" or "json"
+
+	// Elasticsearch is kept for backward compatibility with existing
+	// logger.yaml files. Setup translates it into the equivalent
+	// "elasticsearch" entry of Hooks so it goes through the same hook
+	// registry as everything else.
+	Elasticsearch struct {
+		Enabled            bool   `yaml:"enabled" json:"enabled"`
+		URL                string `yaml:"url" json:"url"`
+		Index              string `yaml:"index" json:"index"`
+		Username           string `yaml:"username" json:"username"`
+		Password           string `yaml:"password" json:"password"`
+		InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+		Retries            int    `yaml:"retries" json:"retries"`
+		QueueSize          int    `yaml:"queue_size" json:"queue_size"`
+		BatchSize          int    `yaml:"batch_size" json:"batch_size"`
+		FlushInterval      string `yaml:"flush_interval" json:"flush_interval"` // duration string, e.g. "5s"
+	} `yaml:"elasticsearch" json:"elasticsearch"`
+
+	// Hooks lists additional sinks to enable, each resolved by name against
+	// the hook registry (see RegisterHook). Example:
+	//
+	//   hooks:
+	//     - name: syslog
+	//       network: udp
+	//       address: localhost:514
+	//     - name: kafka
+	//       brokers: [broker1:9092, broker2:9092]
+	//       topic: hydra-logs
+	Hooks []map[string]interface{} `yaml:"hooks" json:"hooks"`
+
+	// File, if Path is set, sends logger output to a rotatable file instead
+	// of stdout.
+	File struct {
+		Path         string `yaml:"path" json:"path"`
+		Mode         string `yaml:"mode" json:"mode"`                   // octal, e.g. "0644"; defaults to 0644
+		ReopenSignal string `yaml:"reopen_signal" json:"reopen_signal"` // "SIGHUP" (default) or "none"; ignored on Windows
+	} `yaml:"file" json:"file"`
+}
+
+// applyDefaults fills in zero-valued fields with the same defaults Setup
+// has always used, since loadConfig no longer goes through viper.SetDefault.
+func (c *Config) applyDefaults() {
+	if c.Level == "" {
+		c.Level = "info"
+	}
+	if c.ConsoleFormat == "" {
+		c.ConsoleFormat = "text"
+	}
+	if c.Elasticsearch.URL == "" {
+		c.Elasticsearch.URL = "http://localhost:9200"
+	}
+	if c.Elasticsearch.Index == "" {
+		c.Elasticsearch.Index = "logs"
+	}
+	if c.Elasticsearch.Retries == 0 {
+		c.Elasticsearch.Retries = 1
+	}
+}
+
+// Validate rejects config combinations that would silently misbehave.
+func (c Config) Validate() error {
+	var problems []string
+
+	if c.Elasticsearch.Enabled && c.Elasticsearch.URL == "" {
+		problems = append(problems, "elasticsearch.enabled is true but elasticsearch.url is empty")
+	}
+	if c.Elasticsearch.QueueSize > 0 && c.Elasticsearch.BatchSize <= 0 {
+		problems = append(problems, "elasticsearch.batch_size must be > 0 when elasticsearch.queue_size is set")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid logger config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// errConfigNotFound is wrapped by findConfigFile so Setup can distinguish
+// "nothing to load, carry on with defaults" from an actual parse error.
+var errConfigNotFound = errors.New("no configuration file found")
+
+// findConfigFile resolves configPath to the file Setup should read: itself
+// if non-empty, otherwise ./logger.{yaml,yml,json}, falling back to the
+// nearest logger.example.yaml/logger.yaml/logger.yml/logger.json found by
+// walking up ancestor directories.
+func findConfigFile(configPath string) (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+
+	for _, name := range []string{"logger.yaml", "logger.yml", "logger.json"} {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+
+	cwd, _ := os.Getwd()
+	dir := cwd
+	for i := 0; i < 6 && dir != "." && dir != string(filepath.Separator); i++ {
+		for _, name := range []string{"logger.example.yaml", "logger.yaml", "logger.yml", "logger.json"} {
+			cand := filepath.Join(dir, name)
+			if _, err := os.Stat(cand); err == nil {
+				return cand, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", fmt.Errorf("%w (looked in the working directory and its ancestors)", errConfigNotFound)
+}
+
+// loadConfig reads and strictly decodes the logger config at configPath (or
+// the default search locations if configPath is empty), expanding
+// ${VAR}/${VAR:-default} references against the environment first so
+// secrets like passwords can be templated without a separate env-binding
+// call per field. Unknown keys are rejected rather than silently ignored.
+func loadConfig(configPath string) (Config, error) {
+	var cfg Config
+
+	path, err := findConfigFile(configPath)
+	if err != nil {
+		return cfg, err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	expanded := expandEnv(string(raw))
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(strings.NewReader(expanded))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("%s: %w", path, err)
+		}
+		return cfg, nil
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader([]byte(expanded)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// envRefPattern matches only braced ${VAR} / ${VAR:-default} references.
+// Unlike os.Expand, it never treats a bare $name as a reference, so a
+// literal "$" in a value (e.g. a generated password like "p@ss$word123")
+// passes through untouched instead of being looked up as an env var and
+// replaced with "".
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)((:-)([^}]*))?\}`)
+
+// expandEnv replaces ${VAR} and ${VAR:-default} references in s with values
+// from the environment, leaving the reference as an empty string if VAR is
+// unset and no default is given. Any other "$" in s is left untouched.
+func expandEnv(s string) string {
+	return envRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := envRefPattern.FindStringSubmatch(match)
+		name, def, hasDefault := groups[1], groups[4], groups[3] == ":-"
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		if hasDefault {
+			return def
+		}
+		return ""
+	})
+}
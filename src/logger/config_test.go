@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigExpandsEnvAndRejectsUnknownKeys(t *testing.T) {
+	t.Setenv("HYDRA_TEST_ES_PASSWORD", "s3cret")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logger.yaml")
+	const body = `
+level: debug
+elasticsearch:
+  enabled: true
+  url: http://localhost:9200
+  password: ${HYDRA_TEST_ES_PASSWORD}
+  batch_size: ${HYDRA_TEST_BATCH_SIZE:-50}
+`
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if cfg.Elasticsearch.Password != "s3cret" {
+		t.Fatalf("Password = %q, want %q", cfg.Elasticsearch.Password, "s3cret")
+	}
+	if cfg.Elasticsearch.BatchSize != 50 {
+		t.Fatalf("BatchSize = %d, want 50 (from default expansion)", cfg.Elasticsearch.BatchSize)
+	}
+
+	badPath := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(badPath, []byte("level: debug\nconsole_fromat: json\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := loadConfig(badPath); err == nil {
+		t.Fatal("expected an error for an unknown config key, got nil")
+	}
+}
+
+func TestExpandEnvLeavesLiteralDollarSignsAlone(t *testing.T) {
+	got := expandEnv("password: p@ss$word123")
+	if want := "password: p@ss$word123"; got != want {
+		t.Fatalf("expandEnv = %q, want %q", got, want)
+	}
+}
+
+func TestConfigValidateRejectsInconsistentElasticsearchSettings(t *testing.T) {
+	var cfg Config
+	cfg.Elasticsearch.Enabled = true
+	cfg.Elasticsearch.URL = ""
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an error when elasticsearch is enabled with no url")
+	}
+
+	cfg = Config{}
+	cfg.Elasticsearch.QueueSize = 100
+	cfg.Elasticsearch.BatchSize = 0
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an error when queue_size is set but batch_size is not")
+	}
+}
+
+// TestConfigValidateRunsBeforeApplyDefaults pins the call order Setup must
+// use: applyDefaults fills in Elasticsearch.URL unconditionally, so if it ran
+// before Validate an operator's "enabled: true" with a forgotten url would be
+// silently papered over with the localhost default instead of rejected.
+func TestConfigValidateRunsBeforeApplyDefaults(t *testing.T) {
+	var cfg Config
+	cfg.Elasticsearch.Enabled = true
+
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an error when elasticsearch is enabled with no url, before defaults are applied")
+	}
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() after applyDefaults = %v, want nil", err)
+	}
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// correlationIDKey is an unexported type so values stored under it can't
+// collide with keys set by other packages using context.WithValue.
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id as the
+// request's correlation id, picked up by WithContext and, for entries
+// passing through ESHook, shipped to Elasticsearch as a top-level field.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation id stored in ctx, or ""
+// if none was set.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// WithContext returns a Service derived from Log that annotates every call
+// with the correlation id carried by ctx, if any.
+func WithContext(ctx context.Context) Service {
+	if Log == nil {
+		return NewNoopService()
+	}
+	return Log.WithContext(ctx)
+}
+
+// newCorrelationID generates a UUIDv7 correlation id (time-ordered, so it
+// sorts and indexes well in Kibana), falling back to random bytes if the
+// platform's entropy source is briefly unavailable.
+func newCorrelationID() string {
+	if id, err := uuid.NewV7(); err == nil {
+		return id.String()
+	}
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("%x", b)
+}
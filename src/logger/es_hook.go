@@ -0,0 +1,333 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	RegisterHook("elasticsearch", newElasticsearchHook)
+}
+
+// newElasticsearchHook builds the Elasticsearch client and ESHook described
+// by v's settings (url, index, username, password, insecure_skip_verify,
+// retries, queue_size, batch_size, flush_interval) and starts its
+// background worker.
+func newElasticsearchHook(v *viper.Viper) (logrus.Hook, error) {
+	// configure transport so TLS verification can be disabled when using self-signed certs
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if v.GetBool("insecure_skip_verify") {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		} else {
+			transport.TLSClientConfig.InsecureSkipVerify = true
+		}
+	}
+
+	esCfg := elasticsearch.Config{
+		Addresses: []string{v.GetString("url")},
+		Username:  v.GetString("username"),
+		Password:  v.GetString("password"),
+		Transport: transport,
+	}
+	es, err := elasticsearch.NewClient(esCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create elasticsearch client: %w", err)
+	}
+
+	flushInterval, err := time.ParseDuration(v.GetString("flush_interval"))
+	if err != nil {
+		flushInterval = defaultESFlushInterval
+	}
+
+	hook := &ESHook{
+		Client:        es,
+		Index:         v.GetString("index"),
+		Retries:       v.GetInt("retries"),
+		QueueSize:     v.GetInt("queue_size"),
+		BatchSize:     v.GetInt("batch_size"),
+		FlushInterval: flushInterval,
+	}
+	hook.Start()
+	return hook, nil
+}
+
+// defaultESQueueSize and defaultESBatchSize are used when the config does not
+// specify positive values, since with a zero-sized channel Fire would drop
+// every entry the worker is not already waiting to receive.
+const (
+	defaultESQueueSize     = 1000
+	defaultESBatchSize     = 100
+	defaultESFlushInterval = 5 * time.Second
+)
+
+// ESHookStats exposes a snapshot of ESHook's counters for monitoring.
+type ESHookStats struct {
+	Dropped uint64
+	Flushed uint64
+	Failed  uint64
+}
+
+// ESHook indexes log entries into Elasticsearch in background batches rather
+// than blocking the logger goroutine on a per-entry HTTP round-trip. Entries
+// are buffered on a bounded channel and flushed via the Bulk API whenever the
+// batch reaches BatchSize or FlushInterval elapses, whichever comes first.
+type ESHook struct {
+	Client        *elasticsearch.Client
+	Index         string
+	Retries       int
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+
+	entries chan *logrus.Entry
+	quit    chan struct{}
+	stopped chan struct{}
+
+	dropped uint64
+	flushed uint64
+	failed  uint64
+
+	startOnce sync.Once
+	quitOnce  sync.Once
+}
+
+// Levels reports the log levels this hook fires on.
+func (h *ESHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+// Start launches the background worker that batches and flushes entries. It
+// is safe to call multiple times; only the first call has effect.
+func (h *ESHook) Start() {
+	h.startOnce.Do(func() {
+		queueSize := h.QueueSize
+		if queueSize <= 0 {
+			queueSize = defaultESQueueSize
+		}
+		batchSize := h.BatchSize
+		if batchSize <= 0 {
+			batchSize = defaultESBatchSize
+		}
+		h.BatchSize = batchSize
+		flushInterval := h.FlushInterval
+		if flushInterval <= 0 {
+			flushInterval = defaultESFlushInterval
+		}
+		h.FlushInterval = flushInterval
+
+		h.entries = make(chan *logrus.Entry, queueSize)
+		h.quit = make(chan struct{})
+		h.stopped = make(chan struct{})
+
+		go h.run()
+	})
+}
+
+// Fire enqueues entry for background indexing without blocking the caller.
+// If the queue is full the entry is dropped and the Dropped counter in
+// Stats() is incremented so operators can notice sustained back-pressure.
+//
+// entry is buffered directly rather than via entry.Dup(): logrus already
+// hands Fire a private copy (Entry.log() dups the entry, then sets Level and
+// Message on the dup before firing hooks), and Dup() itself does not copy
+// Level, Message, or Caller, so re-duping here would silently drop them.
+func (h *ESHook) Fire(entry *logrus.Entry) error {
+	select {
+	case h.entries <- entry:
+		return nil
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+		return nil
+	}
+}
+
+// Stats returns a snapshot of the hook's counters.
+func (h *ESHook) Stats() ESHookStats {
+	return ESHookStats{
+		Dropped: atomic.LoadUint64(&h.dropped),
+		Flushed: atomic.LoadUint64(&h.flushed),
+		Failed:  atomic.LoadUint64(&h.failed),
+	}
+}
+
+// Close stops accepting new entries, drains and flushes anything already
+// buffered, and waits for the in-flight bulk request to finish or for ctx to
+// be done, whichever comes first. It is safe to call more than once (the
+// shutdown signal is only sent on the first call), and each call honors its
+// own ctx rather than reusing the first caller's. It is also safe to call on
+// a hook that was never Start()ed: Close starts (and immediately stops) the
+// worker rather than closing the not-yet-allocated quit channel.
+func (h *ESHook) Close(ctx context.Context) error {
+	h.Start()
+	h.quitOnce.Do(func() { close(h.quit) })
+	select {
+	case <-h.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run owns the batch buffer and is the only goroutine allowed to touch it.
+func (h *ESHook) run() {
+	defer close(h.stopped)
+
+	buf := make([]*logrus.Entry, 0, h.BatchSize)
+	ticker := time.NewTicker(h.FlushInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(buf) == 0 {
+			return
+		}
+		h.bulkIndex(buf)
+		buf = buf[:0]
+	}
+
+	for {
+		select {
+		case e := <-h.entries:
+			buf = append(buf, e)
+			if len(buf) >= h.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-h.quit:
+			// Drain whatever is already queued, then flush once more.
+			for {
+				select {
+				case e := <-h.entries:
+					buf = append(buf, e)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// bulkIndex sends buf to Elasticsearch via the _bulk API, retrying on 5xx
+// responses and network errors with exponential backoff. 4xx responses are
+// logged once and dropped since retrying them would only spin forever.
+func (h *ESHook) bulkIndex(buf []*logrus.Entry) {
+	body, err := h.encodeBulkBody(buf)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "logger: failed to encode bulk body for ES: %v\n", err)
+		atomic.AddUint64(&h.failed, uint64(len(buf)))
+		return
+	}
+
+	attempts := h.Retries
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	backoff := 100 * time.Millisecond
+	const maxBackoff = 3200 * time.Millisecond
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		res, err := h.Client.Bulk(bytes.NewReader(body), h.Client.Bulk.WithContext(ctx))
+		cancel()
+
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "logger: ES bulk attempt %d/%d failed: %v\n", attempt, attempts, err)
+			if attempt == attempts {
+				atomic.AddUint64(&h.failed, uint64(len(buf)))
+				return
+			}
+			time.Sleep(backoff)
+			if backoff < maxBackoff {
+				backoff *= 2
+			}
+			continue
+		}
+
+		status := res.StatusCode
+		bodyBytes, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+
+		switch {
+		case status >= 200 && status < 300:
+			atomic.AddUint64(&h.flushed, uint64(len(buf)))
+			return
+		case status >= 400 && status < 500:
+			// Poison-pill payload: retrying would never succeed.
+			_, _ = fmt.Fprintf(os.Stderr, "logger: ES bulk rejected batch of %d entries status=%d body=%s\n", len(buf), status, string(bodyBytes))
+			atomic.AddUint64(&h.failed, uint64(len(buf)))
+			return
+		default:
+			_, _ = fmt.Fprintf(os.Stderr, "logger: ES bulk attempt %d/%d status=%d body=%s\n", attempt, attempts, status, string(bodyBytes))
+			if attempt == attempts {
+				atomic.AddUint64(&h.failed, uint64(len(buf)))
+				return
+			}
+			time.Sleep(backoff)
+			if backoff < maxBackoff {
+				backoff *= 2
+			}
+		}
+	}
+}
+
+// encodeBulkBody renders buf as NDJSON: an action line followed by the
+// document line for each entry, as required by the Elasticsearch _bulk API.
+func (h *ESHook) encodeBulkBody(buf []*logrus.Entry) ([]byte, error) {
+	index := h.Index
+	if index == "" {
+		index = "logs"
+	}
+
+	var b bytes.Buffer
+	for _, entry := range buf {
+		action, err := json.Marshal(map[string]interface{}{
+			"index": map[string]interface{}{"_index": index},
+		})
+		if err != nil {
+			return nil, err
+		}
+		b.Write(action)
+		b.WriteByte('\n')
+
+		doc, err := h.encodeEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(doc)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// encodeEntry builds the JSON document shipped to Elasticsearch for a single
+// log entry.
+func (h *ESHook) encodeEntry(entry *logrus.Entry) ([]byte, error) {
+	data := make(map[string]interface{}, len(entry.Data)+3)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	data["@timestamp"] = entry.Time.Format(time.RFC3339)
+	data["message"] = entry.Message
+	data["level"] = entry.Level.String()
+	if entry.Context != nil {
+		if id := CorrelationIDFromContext(entry.Context); id != "" {
+			data["correlation_id"] = id
+		}
+	}
+
+	return json.Marshal(data)
+}
@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestESHook(t *testing.T, handler http.HandlerFunc) *ESHook {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{srv.URL}})
+	if err != nil {
+		t.Fatalf("elasticsearch.NewClient: %v", err)
+	}
+
+	hook := &ESHook{
+		Client:        es,
+		Index:         "logs",
+		Retries:       2,
+		QueueSize:     1,
+		BatchSize:     10,
+		FlushInterval: time.Hour, // only flush when the test calls bulkIndex/Close directly
+	}
+	return hook
+}
+
+func TestESHookFireDropsWhenQueueFull(t *testing.T) {
+	hook := newTestESHook(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	// Don't Start the worker, so nothing ever drains the channel and the
+	// second Fire is guaranteed to see it full.
+	hook.entries = make(chan *logrus.Entry, 1)
+
+	entry := &logrus.Entry{Logger: logrus.StandardLogger(), Message: "hello"}
+	if err := hook.Fire(entry); err != nil {
+		t.Fatalf("first Fire: %v", err)
+	}
+	if err := hook.Fire(entry); err != nil {
+		t.Fatalf("second Fire: %v", err)
+	}
+
+	if got := hook.Stats().Dropped; got != 1 {
+		t.Fatalf("Dropped = %d, want 1", got)
+	}
+}
+
+func TestFirePreservesMessageAndLevel(t *testing.T) {
+	hook := newTestESHook(t, func(w http.ResponseWriter, r *http.Request) {})
+	hook.entries = make(chan *logrus.Entry, 1)
+
+	log := logrus.New()
+	log.Out = io.Discard
+	log.AddHook(hook)
+	log.WithField("k", "v").Warn("hello")
+
+	select {
+	case entry := <-hook.entries:
+		if entry.Message != "hello" {
+			t.Fatalf("Message = %q, want %q", entry.Message, "hello")
+		}
+		if entry.Level != logrus.WarnLevel {
+			t.Fatalf("Level = %v, want %v", entry.Level, logrus.WarnLevel)
+		}
+		if entry.Data["k"] != "v" {
+			t.Fatalf("Data[k] = %v, want %q", entry.Data["k"], "v")
+		}
+	default:
+		t.Fatal("expected an entry to have been queued on hook.entries")
+	}
+}
+
+func TestCloseHonorsEachCallersContext(t *testing.T) {
+	started := make(chan struct{})
+	var startedOnce sync.Once
+	release := make(chan struct{})
+	hook := newTestESHook(t, func(w http.ResponseWriter, r *http.Request) {
+		startedOnce.Do(func() { close(started) })
+		<-release
+		// go-elasticsearch validates an X-Elastic-Product header on 2xx
+		// responses; set it so a successful response isn't itself treated
+		// as a client error that triggers another retry.
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.WriteHeader(http.StatusOK)
+	})
+	hook.BatchSize = 1
+	hook.FlushInterval = time.Hour
+	hook.Start()
+
+	if err := hook.Fire(&logrus.Entry{Logger: logrus.StandardLogger(), Message: "slow"}); err != nil {
+		t.Fatalf("Fire: %v", err)
+	}
+	<-started // the worker is now blocked inside bulkIndex's HTTP call
+
+	shortCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := hook.Close(shortCtx); err == nil {
+		t.Fatal("expected the first Close with a short timeout to report ctx.Err() while the worker is still flushing")
+	}
+
+	close(release)
+
+	if err := hook.Close(context.Background()); err != nil {
+		t.Fatalf("second Close should wait for the worker to finish and succeed: %v", err)
+	}
+}
+
+func TestCloseOnUnstartedHookDoesNotPanic(t *testing.T) {
+	hook := newTestESHook(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	if err := hook.Close(context.Background()); err != nil {
+		t.Fatalf("Close on an unstarted hook: %v", err)
+	}
+}
+
+func TestEncodeBulkBodyProducesActionAndDocLines(t *testing.T) {
+	hook := newTestESHook(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	entries := []*logrus.Entry{
+		{Logger: logrus.StandardLogger(), Message: "one", Level: logrus.InfoLevel, Data: logrus.Fields{"k": "v"}},
+		{Logger: logrus.StandardLogger(), Message: "two", Level: logrus.WarnLevel},
+	}
+
+	body, err := hook.encodeBulkBody(entries)
+	if err != nil {
+		t.Fatalf("encodeBulkBody: %v", err)
+	}
+
+	lines := splitLines(body)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4 (action+doc per entry): %s", len(lines), body)
+	}
+
+	var action map[string]map[string]interface{}
+	if err := json.Unmarshal(lines[0], &action); err != nil {
+		t.Fatalf("unmarshal action line: %v", err)
+	}
+	if action["index"]["_index"] != "logs" {
+		t.Fatalf("action line = %s, want _index=logs", lines[0])
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(lines[1], &doc); err != nil {
+		t.Fatalf("unmarshal doc line: %v", err)
+	}
+	if doc["message"] != "one" || doc["k"] != "v" || doc["level"] != "info" {
+		t.Fatalf("doc line = %s, missing expected fields", lines[1])
+	}
+}
+
+func splitLines(body []byte) [][]byte {
+	var lines [][]byte
+	start := 0
+	for i, b := range body {
+		if b == '\n' {
+			if i > start {
+				lines = append(lines, body[start:i])
+			}
+			start = i + 1
+		}
+	}
+	return lines
+}
+
+func TestBulkIndexDropsOn4xxWithoutRetry(t *testing.T) {
+	var calls int32
+	hook := newTestESHook(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	hook.bulkIndex([]*logrus.Entry{{Logger: logrus.StandardLogger(), Message: "bad"}})
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("server called %d times, want exactly 1 (no retry on 4xx)", got)
+	}
+	if got := hook.Stats().Failed; got != 1 {
+		t.Fatalf("Failed = %d, want 1", got)
+	}
+}
+
+func TestBulkIndexRetriesOn5xx(t *testing.T) {
+	var calls int32
+	hook := newTestESHook(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	hook.bulkIndex([]*logrus.Entry{{Logger: logrus.StandardLogger(), Message: "oops"}})
+
+	if got := atomic.LoadInt32(&calls); got != int32(hook.Retries) {
+		t.Fatalf("server called %d times, want %d (retries exhausted on 5xx)", got, hook.Retries)
+	}
+	if got := hook.Stats().Failed; got != 1 {
+		t.Fatalf("Failed = %d, want 1", got)
+	}
+}
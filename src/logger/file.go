@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// reopenWriter is an io.Writer over a log file that can be atomically
+// swapped for a freshly opened handle to the same path, so external tools
+// like logrotate (with copytruncate off) can rotate the file without Hydra
+// restarting.
+type reopenWriter struct {
+	mu   sync.RWMutex
+	path string
+	mode os.FileMode
+	file *os.File
+}
+
+// newReopenWriter opens path (creating it with mode if it doesn't exist)
+// and returns a writer over it.
+func newReopenWriter(path string, mode os.FileMode) (*reopenWriter, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file %q: %w", path, err)
+	}
+	return &reopenWriter{path: path, mode: mode, file: f}, nil
+}
+
+// Write implements io.Writer against the current file handle.
+func (w *reopenWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.file.Write(p)
+}
+
+// Reopen opens path afresh - picking up a rename-and-recreate done by an
+// external tool - and swaps it in, closing the previous handle only after
+// any in-flight Write has released the read lock.
+func (w *reopenWriter) Reopen() error {
+	newFile, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, w.mode)
+	if err != nil {
+		return fmt.Errorf("failed to reopen log file %q: %w", w.path, err)
+	}
+
+	w.mu.Lock()
+	old := w.file
+	w.file = newFile
+	w.mu.Unlock()
+
+	return old.Close()
+}
+
+// activeFileWriter is set by Setup when a file: section is configured, so
+// the package-level Reopen can reach it.
+var (
+	activeFileWriterMu sync.Mutex
+	activeFileWriter   *reopenWriter
+)
+
+// Reopen re-opens the configured log file in place, picking up a rotation
+// performed by an external tool such as logrotate. It is a no-op if Setup
+// was not given a file: section. The SIGHUP handler registered by Setup
+// calls this automatically; it is exported for programmatic invocation too.
+func Reopen() error {
+	activeFileWriterMu.Lock()
+	fw := activeFileWriter
+	activeFileWriterMu.Unlock()
+
+	if fw == nil {
+		return nil
+	}
+	return fw.Reopen()
+}
@@ -0,0 +1,73 @@
+//go:build !windows
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRegisterReopenSignalPicksUpRotatedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hydra.log")
+
+	fw, err := newReopenWriter(path, 0644)
+	if err != nil {
+		t.Fatalf("newReopenWriter: %v", err)
+	}
+	registerReopenSignal(fw, "SIGHUP")
+
+	if _, err := fw.Write([]byte("before\n")); err != nil {
+		t.Fatalf("Write before rotate: %v", err)
+	}
+
+	rotated := path + ".1"
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("FindProcess: %v", err)
+	}
+	if err := proc.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("Signal: %v", err)
+	}
+
+	// The handler reopens asynchronously; poll briefly instead of assuming
+	// it has already run.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got, readErr := os.ReadFile(path)
+		if readErr == nil && len(got) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("new file never appeared empty after rotate+SIGHUP (content=%q, err=%v)", got, readErr)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := fw.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write after rotate: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "after\n" {
+		t.Fatalf("new file content = %q, want %q", string(got), "after\n")
+	}
+
+	rotatedContent, err := os.ReadFile(rotated)
+	if err != nil {
+		t.Fatalf("ReadFile rotated: %v", err)
+	}
+	if string(rotatedContent) != "before\n" {
+		t.Fatalf("rotated file content = %q, want %q", string(rotatedContent), "before\n")
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	RegisterHook("kafka", newKafkaHook)
+}
+
+// kafkaHook ships each log entry as a JSON document to a Kafka topic. The
+// writer is async so Fire never blocks on the broker round-trip.
+type kafkaHook struct {
+	writer *kafka.Writer
+}
+
+// newKafkaHook builds a kafkaHook from v's settings: brokers (a list of
+// host:port strings), topic, and an optional required_acks ("none", "one",
+// "all"; defaults to "one").
+func newKafkaHook(v *viper.Viper) (logrus.Hook, error) {
+	brokers := v.GetStringSlice("brokers")
+	topic := v.GetString("topic")
+	if len(brokers) == 0 || topic == "" {
+		return nil, fmt.Errorf("kafka hook requires brokers and topic")
+	}
+
+	requiredAcks := kafka.RequireOne
+	switch v.GetString("required_acks") {
+	case "none":
+		requiredAcks = kafka.RequireNone
+	case "all":
+		requiredAcks = kafka.RequireAll
+	}
+
+	return &kafkaHook{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.LeastBytes{},
+		Async:        true,
+		RequiredAcks: requiredAcks,
+	}}, nil
+}
+
+func (h *kafkaHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (h *kafkaHook) Fire(entry *logrus.Entry) error {
+	data := make(map[string]interface{}, len(entry.Data)+2)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	data["@timestamp"] = entry.Time.Format(time.RFC3339)
+	data["message"] = entry.Message
+	data["level"] = entry.Level.String()
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return h.writer.WriteMessages(context.Background(), kafka.Message{Value: b})
+}
+
+// Close flushes the async writer and closes its connections, waiting for
+// that to finish or for ctx to be done, whichever comes first, so a caller's
+// shutdown deadline is honored even if the writer hangs flushing its queue.
+func (h *kafkaHook) Close(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() { done <- h.writer.Close() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	RegisterHook("stdout_json", newStdoutJSONHook)
+}
+
+// stdoutJSONHook writes each entry as a logstash-compatible JSON line
+// (@timestamp, @version, level, message, plus the entry's fields) to out,
+// defaulting to os.Stdout.
+type stdoutJSONHook struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func newStdoutJSONHook(v *viper.Viper) (logrus.Hook, error) {
+	return &stdoutJSONHook{out: os.Stdout}, nil
+}
+
+func (h *stdoutJSONHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (h *stdoutJSONHook) Fire(entry *logrus.Entry) error {
+	data := make(map[string]interface{}, len(entry.Data)+4)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	data["@timestamp"] = entry.Time.Format(time.RFC3339)
+	data["@version"] = "1"
+	data["level"] = entry.Level.String()
+	data["message"] = entry.Message
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = fmt.Fprintln(h.out, string(b))
+	return err
+}
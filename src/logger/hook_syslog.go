@@ -0,0 +1,52 @@
+//go:build !windows
+
+package logger
+
+import (
+	"log/syslog"
+
+	"github.com/sirupsen/logrus"
+	logrus_syslog "github.com/sirupsen/logrus/hooks/syslog"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	RegisterHook("syslog", newSyslogHook)
+}
+
+// newSyslogHook ships entries to a syslog daemon over log/syslog. network
+// and address follow net.Dial conventions (e.g. "udp", "localhost:514");
+// leave both empty to log to the local syslog via the unix socket.
+func newSyslogHook(v *viper.Viper) (logrus.Hook, error) {
+	network := v.GetString("network")
+	address := v.GetString("address")
+	tag := v.GetString("tag")
+	if tag == "" {
+		tag = "hydra"
+	}
+
+	return logrus_syslog.NewSyslogHook(network, address, syslogPriority(v.GetString("priority")), tag)
+}
+
+// syslogPriority maps a config string to a syslog.Priority, defaulting to
+// LOG_INFO for an empty or unrecognized value.
+func syslogPriority(name string) syslog.Priority {
+	switch name {
+	case "emerg":
+		return syslog.LOG_EMERG
+	case "alert":
+		return syslog.LOG_ALERT
+	case "crit":
+		return syslog.LOG_CRIT
+	case "err", "error":
+		return syslog.LOG_ERR
+	case "warning", "warn":
+		return syslog.LOG_WARNING
+	case "notice":
+		return syslog.LOG_NOTICE
+	case "debug":
+		return syslog.LOG_DEBUG
+	default:
+		return syslog.LOG_INFO
+	}
+}
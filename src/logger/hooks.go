@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// HookFactory builds a logrus.Hook from a hook's own config sub-tree (the
+// settings map under one entry of the `hooks:` section, which still
+// includes the `name` key used to look the factory up).
+type HookFactory func(v *viper.Viper) (logrus.Hook, error)
+
+var (
+	hookFactoriesMu sync.Mutex
+	hookFactories   = map[string]HookFactory{}
+)
+
+// RegisterHook makes factory available under name for the `hooks:` section
+// of logger config. Third-party packages can call this from an init() -
+// before Setup runs - to plug in sinks (e.g. "appinsights") without
+// modifying this package.
+func RegisterHook(name string, factory HookFactory) {
+	hookFactoriesMu.Lock()
+	defer hookFactoriesMu.Unlock()
+	hookFactories[name] = factory
+}
+
+func lookupHookFactory(name string) (HookFactory, bool) {
+	hookFactoriesMu.Lock()
+	defer hookFactoriesMu.Unlock()
+	factory, ok := hookFactories[name]
+	return factory, ok
+}
+
+// hookCloser is implemented by hooks whose background work (e.g. a batching
+// goroutine) needs to be stopped on shutdown. Setup registers it with
+// Close so logger.Close(ctx) can drain it alongside everything else.
+type hookCloser interface {
+	Close(ctx context.Context) error
+}
+
+// hookSpec is one entry to be resolved against the hook registry: either a
+// `hooks:` list item, or the legacy top-level `elasticsearch:` block
+// translated into the equivalent registry entry for backward compatibility.
+type hookSpec struct {
+	name     string
+	settings map[string]interface{}
+}
+
+// buildHooks resolves each spec against the hook registry and adds the
+// resulting hooks to log, registering any that need an orderly shutdown.
+func buildHooks(log *logrus.Logger, specs []hookSpec) {
+	for _, spec := range specs {
+		factory, ok := lookupHookFactory(spec.name)
+		if !ok {
+			logSetupErrorf("no hook factory registered for %q, skipping", spec.name)
+			continue
+		}
+
+		hv := viper.New()
+		if err := hv.MergeConfigMap(spec.settings); err != nil {
+			logSetupErrorf("failed to read config for hook %q: %v", spec.name, err)
+			continue
+		}
+
+		hook, err := factory(hv)
+		if err != nil {
+			logSetupErrorf("failed to create hook %q: %v", spec.name, err)
+			continue
+		}
+
+		log.AddHook(hook)
+		if closer, ok := hook.(hookCloser); ok {
+			closersMu.Lock()
+			closers = append(closers, closer.Close)
+			closersMu.Unlock()
+		}
+	}
+}
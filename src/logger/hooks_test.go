@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestStdoutJSONHookFireEmitsLogstashShape(t *testing.T) {
+	var buf bytes.Buffer
+	hook := &stdoutJSONHook{out: &buf}
+
+	entry := &logrus.Entry{
+		Logger:  logrus.StandardLogger(),
+		Message: "hello",
+		Level:   logrus.InfoLevel,
+		Data:    logrus.Fields{"k": "v"},
+	}
+	if err := hook.Fire(entry); err != nil {
+		t.Fatalf("Fire: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal output %q: %v", buf.String(), err)
+	}
+
+	if got["@version"] != "1" {
+		t.Errorf("@version = %v, want %q", got["@version"], "1")
+	}
+	if _, ok := got["@timestamp"]; !ok {
+		t.Error("missing @timestamp field")
+	}
+	if got["level"] != "info" {
+		t.Errorf("level = %v, want %q", got["level"], "info")
+	}
+	if got["message"] != "hello" {
+		t.Errorf("message = %v, want %q", got["message"], "hello")
+	}
+	if got["k"] != "v" {
+		t.Errorf("k = %v, want %q", got["k"], "v")
+	}
+}
+
+func TestBuildHooksSkipsUnknownFactoryName(t *testing.T) {
+	log := logrus.New()
+	specs := []hookSpec{{name: "does-not-exist", settings: map[string]interface{}{}}}
+
+	// Should not panic and should not add any hook for the unknown name.
+	buildHooks(log, specs)
+
+	if got := len(log.Hooks[logrus.InfoLevel]); got != 0 {
+		t.Fatalf("got %d hooks registered, want 0 for an unknown factory name", got)
+	}
+}
+
+func TestBuildHooksRegistersKnownFactory(t *testing.T) {
+	log := logrus.New()
+	specs := []hookSpec{{name: "stdout_json", settings: map[string]interface{}{}}}
+
+	buildHooks(log, specs)
+
+	found := false
+	for _, h := range log.Hooks[logrus.InfoLevel] {
+		if _, ok := h.(*stdoutJSONHook); ok {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected a stdout_json hook to be registered")
+	}
+}
+
+func TestBuildHooksSkipsBadSettingsMap(t *testing.T) {
+	log := logrus.New()
+	// kafka requires brokers/topic; an empty settings map makes the
+	// factory itself return an error, which buildHooks should log and skip
+	// rather than propagate.
+	specs := []hookSpec{{name: "kafka", settings: map[string]interface{}{}}}
+
+	buildHooks(log, specs)
+
+	if got := len(log.Hooks[logrus.InfoLevel]); got != 0 {
+		t.Fatalf("got %d hooks registered, want 0 for a rejected factory", got)
+	}
+}
+
+func TestLookupHookFactoryReportsPresence(t *testing.T) {
+	if _, ok := lookupHookFactory("does-not-exist-" + strings.Repeat("x", 4)); ok {
+		t.Fatal("lookupHookFactory reported a factory for a name that was never registered")
+	}
+	if _, ok := lookupHookFactory("stdout_json"); !ok {
+		t.Fatal("lookupHookFactory did not find the built-in stdout_json factory")
+	}
+}
@@ -1,106 +1,46 @@
 package logger
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"path/filepath"
+	"strconv"
+	"sync"
 	"time"
 
-	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
-// Config represents logger configuration loaded from a file.
-type Config struct {
-	Level         string `mapstructure:"level"`
-	ConsoleFormat string `mapstructure:"console_format"` // "text" or "json"
-	Elasticsearch struct {
-		Enabled            bool   `mapstructure:"enabled"`
-		URL                string `mapstructure:"url"`
-		Index              string `mapstructure:"index"`
-		Username           string `mapstructure:"username"`
-		Password           string `mapstructure:"password"`
-		InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
-		Retries            int    `mapstructure:"retries"`
-		QueueSize          int    `mapstructure:"queue_size"`
-		BatchSize          int    `mapstructure:"batch_size"`
-		FlushInterval      string `mapstructure:"flush_interval"` // duration string
-	} `mapstructure:"elasticsearch"`
-}
-
-// Log is the package-level logger instance.
-var Log *logrus.Logger
+// Log is the package-level logger instance. It is typed as Service, not
+// *logrus.Logger, so call sites depend on the minimal facade rather than a
+// concrete backend.
+var Log Service
+
+// closers collects shutdown hooks registered by Setup (the Close method of
+// every hook that has one, such as the ESHook's background worker and the
+// Kafka writer) so Close can stop them all together.
+var (
+	closersMu sync.Mutex
+	closers   []func(context.Context) error
+)
 
 // Setup reads configuration from the provided path and configures the logger.
-// If configPath is empty it will try to read ./logger.yaml or ./logger.json.
+// If configPath is empty it will try to read ./logger.yaml or ./logger.json,
+// falling back to the nearest one found in an ancestor directory.
 func Setup(configPath string) error {
-	v := viper.New()
-	// sensible defaults so env-only configs work
-	v.SetDefault("level", "info")
-	v.SetDefault("console_format", "text")
-	v.SetDefault("elasticsearch.enabled", false)
-	v.SetDefault("elasticsearch.url", "http://localhost:9200")
-	v.SetDefault("elasticsearch.index", "logs")
-	v.SetDefault("elasticsearch.insecure_skip_verify", false)
-	v.SetDefault("elasticsearch.retries", 1)
-
-	if configPath == "" {
-		v.SetConfigName("logger")
-		v.AddConfigPath(".")
-	} else {
-		v.SetConfigFile(configPath)
-	}
-	v.SetEnvPrefix("LOGGER")
-	v.AutomaticEnv()
-	_ = v.BindEnv("elasticsearch.insecure_skip_verify", "ELASTIC_INSECURE_SKIP_VERIFY")
-	_ = v.BindEnv("elasticsearch.retries", "ELASTIC_RETRIES")
-
-	// Try to read config from the usual locations. If not found, search up
-	// ancestor directories for logger.example.yaml or logger.yaml as a fallback.
-	if err := v.ReadInConfig(); err != nil {
-		cwd, _ := os.Getwd()
-		dir := cwd
-		found := false
-		for i := 0; i < 6 && dir != "." && dir != string(filepath.Separator); i++ {
-			candidates := []string{
-				filepath.Join(dir, "logger.example.yaml"),
-				filepath.Join(dir, "logger.yaml"),
-				filepath.Join(dir, "logger.yml"),
-			}
-			for _, cand := range candidates {
-				if _, statErr := os.Stat(cand); statErr == nil {
-					v.SetConfigFile(cand)
-					if rcErr := v.ReadInConfig(); rcErr == nil {
-						found = true
-						break
-					}
-				}
-			}
-			if found {
-				break
-			}
-			parent := filepath.Dir(dir)
-			if parent == dir {
-				break
-			}
-			dir = parent
-		}
-		if !found {
-			_, _ = fmt.Fprintf(os.Stderr, "logger: no configuration file found: %v\n", err)
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			logSetupErrorf("%v, using defaults", err)
+		} else {
+			return fmt.Errorf("failed to load logger config: %w", err)
 		}
 	}
-
-	var cfg Config
-	if err := v.Unmarshal(&cfg); err != nil {
-		return fmt.Errorf("failed to decode logger config: %w", err)
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
+	cfg.applyDefaults()
 
 	log := logrus.New()
 	// Formatter
@@ -118,105 +58,73 @@ func Setup(configPath string) error {
 	}
 	log.SetLevel(level)
 
-	// Output to stdout
-	log.SetOutput(os.Stdout)
-
-	// Elasticsearch hook: configure client and add hook if enabled
-	if cfg.Elasticsearch.Enabled {
-		// configure transport so TLS verification can be disabled when using self-signed certs
-		transport := http.DefaultTransport.(*http.Transport).Clone()
-		if cfg.Elasticsearch.InsecureSkipVerify {
-			if transport.TLSClientConfig == nil {
-				transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	// Output: a rotatable file if file.path is set, otherwise stdout.
+	if cfg.File.Path != "" {
+		mode := os.FileMode(0644)
+		if cfg.File.Mode != "" {
+			if parsed, parseErr := strconv.ParseUint(cfg.File.Mode, 8, 32); parseErr == nil {
+				mode = os.FileMode(parsed)
 			} else {
-				transport.TLSClientConfig.InsecureSkipVerify = true
+				logSetupErrorf("invalid file.mode %q, using 0644: %v", cfg.File.Mode, parseErr)
 			}
 		}
 
-		esCfg := elasticsearch.Config{
-			Addresses: []string{cfg.Elasticsearch.URL},
-			Username:  cfg.Elasticsearch.Username,
-			Password:  cfg.Elasticsearch.Password,
-			Transport: transport,
-		}
-		es, err := elasticsearch.NewClient(esCfg)
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "logger: failed to create elasticsearch client: %v\n", err)
+		fw, ferr := newReopenWriter(cfg.File.Path, mode)
+		if ferr != nil {
+			logSetupErrorf("%v, falling back to stdout", ferr)
+			log.SetOutput(os.Stdout)
 		} else {
-			// do not print ES Info on startup in production; keep hook installation silent
-			hook := &ESHook{Client: es, Index: cfg.Elasticsearch.Index, Retries: cfg.Elasticsearch.Retries}
-			log.AddHook(hook)
-		}
-	}
-
-	Log = log
-	return nil
-}
+			log.SetOutput(fw)
+			registerReopenSignal(fw, cfg.File.ReopenSignal)
 
-// ESHook indexes each log entry into Elasticsearch (best-effort, non-fatal).
-type ESHook struct {
-	Client  *elasticsearch.Client
-	Index   string
-	Retries int
-}
-
-func (h *ESHook) Levels() []logrus.Level { return logrus.AllLevels }
-
-func (h *ESHook) Fire(entry *logrus.Entry) error {
-	// Prepare payload
-	data := make(map[string]interface{})
-	for k, v := range entry.Data {
-		data[k] = v
-	}
-	// Add fields expected by Kibana: @timestamp and message
-	data["@timestamp"] = entry.Time.Format(time.RFC3339)
-	data["message"] = entry.Message
-	data["level"] = entry.Level.String()
-	data["timestamp"] = entry.Time.Format(time.RFC3339)
-
-	b, err := json.Marshal(data)
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "logger: failed to marshal log entry for ES: %v\n", err)
-		return nil
-	}
-
-	index := h.Index
-	if index == "" {
-		index = "logs"
+			activeFileWriterMu.Lock()
+			activeFileWriter = fw
+			activeFileWriterMu.Unlock()
+		}
+	} else {
+		log.SetOutput(os.Stdout)
 	}
 
-	// Use a short timeout for indexing so hook doesn't block indefinitely
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	attempts := 1
-	if h.Retries > 0 {
-		attempts = h.Retries
+	// Build the hook pipeline: the legacy elasticsearch: block first (for
+	// backward compatibility), then whatever is listed under hooks:.
+	var specs []hookSpec
+	if cfg.Elasticsearch.Enabled {
+		specs = append(specs, hookSpec{
+			name: "elasticsearch",
+			settings: map[string]interface{}{
+				"url":                  cfg.Elasticsearch.URL,
+				"index":                cfg.Elasticsearch.Index,
+				"username":             cfg.Elasticsearch.Username,
+				"password":             cfg.Elasticsearch.Password,
+				"insecure_skip_verify": cfg.Elasticsearch.InsecureSkipVerify,
+				"retries":              cfg.Elasticsearch.Retries,
+				"queue_size":           cfg.Elasticsearch.QueueSize,
+				"batch_size":           cfg.Elasticsearch.BatchSize,
+				"flush_interval":       cfg.Elasticsearch.FlushInterval,
+			},
+		})
 	}
-
-	for i := 0; i < attempts; i++ {
-		res, err := h.Client.Index(index, bytes.NewReader(b), h.Client.Index.WithContext(ctx), h.Client.Index.WithRefresh("true"))
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "logger: ES index attempt %d/%d failed: %v\n", i+1, attempts, err)
-			if ctx.Err() != nil {
-				break
-			}
-			time.Sleep(100 * time.Millisecond)
+	for i, h := range cfg.Hooks {
+		name, _ := h["name"].(string)
+		if name == "" {
+			logSetupErrorf("hooks[%d] is missing a name, skipping", i)
 			continue
 		}
-		if res != nil {
-			bodyBytes, _ := io.ReadAll(res.Body)
-			_ = res.Body.Close()
-			if res.StatusCode < 200 || res.StatusCode >= 300 {
-				_, _ = fmt.Fprintf(os.Stderr, "logger: ES responded with status=%d on attempt %d/%d body=%s\n", res.StatusCode, i+1, attempts, string(bodyBytes))
-			}
-		}
-		break
+		specs = append(specs, hookSpec{name: name, settings: h})
 	}
+	buildHooks(log, specs)
 
+	Log = NewService(log)
 	return nil
 }
 
+// logSetupErrorf writes a "logger: "-prefixed diagnostic to stderr. Setup
+// uses this for problems that shouldn't prevent the rest of the logger from
+// coming up (a bad hook config, an unreachable sink).
+func logSetupErrorf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(os.Stderr, "logger: "+format+"\n", args...)
+}
+
 // MustSetup exits on error.
 func MustSetup(configPath string) {
 	if err := Setup(configPath); err != nil {
@@ -225,5 +133,21 @@ func MustSetup(configPath string) {
 	}
 }
 
-// keep reference so linters don't complain
-var _ = Log
+// Close shuts down any background workers registered during Setup (such as
+// the ESHook's batching goroutine), flushing buffered entries before ctx is
+// done. Callers of MustSetup should defer logger.Close(ctx) so buffered log
+// entries are not lost on process exit.
+func Close(ctx context.Context) error {
+	closersMu.Lock()
+	toClose := closers
+	closers = nil
+	closersMu.Unlock()
+
+	var firstErr error
+	for _, closeFn := range toClose {
+		if err := closeFn(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
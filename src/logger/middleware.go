@@ -0,0 +1,23 @@
+package logger
+
+import "net/http"
+
+// RequestIDHeader is the header used to propagate a correlation id across
+// service boundaries.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware reads RequestIDHeader from the incoming request, or
+// generates a UUIDv7 when absent, stores it on the request context (so
+// logger.WithContext(r.Context()) picks it up automatically) and echoes it
+// back on the response so the caller can correlate its own logs.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = newCorrelationID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(ContextWithCorrelationID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDMiddlewareGeneratesAndEchoesID(t *testing.T) {
+	var gotID string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = CorrelationIDFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	RequestIDMiddleware(next).ServeHTTP(rec, req)
+
+	if gotID == "" {
+		t.Fatal("expected a generated correlation id on the request context")
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != gotID {
+		t.Fatalf("response header %s = %q, want %q", RequestIDHeader, got, gotID)
+	}
+}
+
+func TestRequestIDMiddlewarePreservesIncomingID(t *testing.T) {
+	var gotID string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = CorrelationIDFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "incoming-id")
+	rec := httptest.NewRecorder()
+	RequestIDMiddleware(next).ServeHTTP(rec, req)
+
+	if gotID != "incoming-id" {
+		t.Fatalf("CorrelationIDFromContext = %q, want %q", gotID, "incoming-id")
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != "incoming-id" {
+		t.Fatalf("response header %s = %q, want %q", RequestIDHeader, got, "incoming-id")
+	}
+}
+
+func TestWithContextAnnotatesNoopService(t *testing.T) {
+	Log = NewNoopService()
+	defer func() { Log = nil }()
+
+	ctx := ContextWithCorrelationID(context.Background(), "abc-123")
+	WithContext(ctx).Infof("hi")
+
+	records := Log.(*NoopService).Records()
+	if len(records) != 1 || records[0].Fields["correlation_id"] != "abc-123" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+}
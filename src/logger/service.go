@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Service is a minimal logging facade that call sites should depend on
+// instead of a concrete backend such as *logrus.Logger. This mirrors the
+// pattern used by cloudflared's logger package: it keeps call sites portable
+// across backends (logrus today, zap/slog/a testing recorder tomorrow) and
+// lets tests assert on logged fields without capturing real output streams.
+type Service interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+	WithField(key string, value interface{}) Service
+	WithError(err error) Service
+
+	// WithContext returns a Service that carries ctx, annotating every call
+	// with a correlation_id field when ctx has one set via
+	// ContextWithCorrelationID (e.g. by RequestIDMiddleware).
+	WithContext(ctx context.Context) Service
+}
+
+// logrusService is the default Service implementation, backed by a
+// *logrus.Entry so chained WithField/WithError calls accumulate fields the
+// way logrus callers already expect.
+type logrusService struct {
+	entry *logrus.Entry
+}
+
+// NewService wraps log as a Service.
+func NewService(log *logrus.Logger) Service {
+	return &logrusService{entry: logrus.NewEntry(log)}
+}
+
+func (s *logrusService) Debugf(format string, args ...interface{}) { s.entry.Debugf(format, args...) }
+func (s *logrusService) Infof(format string, args ...interface{})  { s.entry.Infof(format, args...) }
+func (s *logrusService) Warnf(format string, args ...interface{})  { s.entry.Warnf(format, args...) }
+func (s *logrusService) Errorf(format string, args ...interface{}) { s.entry.Errorf(format, args...) }
+func (s *logrusService) Fatalf(format string, args ...interface{}) { s.entry.Fatalf(format, args...) }
+
+func (s *logrusService) WithField(key string, value interface{}) Service {
+	return &logrusService{entry: s.entry.WithField(key, value)}
+}
+
+func (s *logrusService) WithError(err error) Service {
+	return &logrusService{entry: s.entry.WithError(err)}
+}
+
+func (s *logrusService) WithContext(ctx context.Context) Service {
+	entry := s.entry.WithContext(ctx)
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		entry = entry.WithField("correlation_id", id)
+	}
+	return &logrusService{entry: entry}
+}
+
+// ServiceRecord is one logged call captured by a NoopService, exposed so
+// tests can assert on it directly instead of scraping formatted output.
+type ServiceRecord struct {
+	Level   string
+	Message string
+	Fields  map[string]interface{}
+	Err     error
+}
+
+// noopRecorder is shared by a NoopService and every Service it derives via
+// WithField/WithError, so records logged through a derived instance are
+// still visible from the root.
+type noopRecorder struct {
+	mu      sync.Mutex
+	records []ServiceRecord
+}
+
+func (r *noopRecorder) append(rec ServiceRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+}
+
+func (r *noopRecorder) snapshot() []ServiceRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]ServiceRecord, len(r.records))
+	copy(out, r.records)
+	return out
+}
+
+// NoopService is a Service implementation that records calls in memory
+// instead of writing anywhere, for use in tests.
+type NoopService struct {
+	rec    *noopRecorder
+	fields map[string]interface{}
+	err    error
+}
+
+// NewNoopService returns a Service that records calls instead of logging
+// them, for assertions in tests.
+func NewNoopService() *NoopService {
+	return &NoopService{rec: &noopRecorder{}, fields: map[string]interface{}{}}
+}
+
+// Records returns a snapshot of every call logged through this service or
+// any Service derived from it via WithField/WithError.
+func (s *NoopService) Records() []ServiceRecord { return s.rec.snapshot() }
+
+func (s *NoopService) log(level, format string, args ...interface{}) {
+	s.rec.append(ServiceRecord{
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  s.fields,
+		Err:     s.err,
+	})
+}
+
+func (s *NoopService) Debugf(format string, args ...interface{}) { s.log("debug", format, args...) }
+func (s *NoopService) Infof(format string, args ...interface{})  { s.log("info", format, args...) }
+func (s *NoopService) Warnf(format string, args ...interface{})  { s.log("warning", format, args...) }
+func (s *NoopService) Errorf(format string, args ...interface{}) { s.log("error", format, args...) }
+func (s *NoopService) Fatalf(format string, args ...interface{}) { s.log("fatal", format, args...) }
+
+func (s *NoopService) WithField(key string, value interface{}) Service {
+	fields := make(map[string]interface{}, len(s.fields)+1)
+	for k, v := range s.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	return &NoopService{rec: s.rec, fields: fields, err: s.err}
+}
+
+func (s *NoopService) WithError(err error) Service {
+	return &NoopService{rec: s.rec, fields: s.fields, err: err}
+}
+
+func (s *NoopService) WithContext(ctx context.Context) Service {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		return s.WithField("correlation_id", id)
+	}
+	return s
+}
+
+// teeService fans every call out to a fixed set of Services, so e.g. the
+// logrus backend and a test recorder can both observe the same calls.
+type teeService struct {
+	services []Service
+}
+
+// Tee combines services into a single Service that forwards every call to
+// each of them.
+func Tee(services ...Service) Service {
+	return &teeService{services: services}
+}
+
+func (t *teeService) Debugf(format string, args ...interface{}) {
+	for _, s := range t.services {
+		s.Debugf(format, args...)
+	}
+}
+
+func (t *teeService) Infof(format string, args ...interface{}) {
+	for _, s := range t.services {
+		s.Infof(format, args...)
+	}
+}
+
+func (t *teeService) Warnf(format string, args ...interface{}) {
+	for _, s := range t.services {
+		s.Warnf(format, args...)
+	}
+}
+
+func (t *teeService) Errorf(format string, args ...interface{}) {
+	for _, s := range t.services {
+		s.Errorf(format, args...)
+	}
+}
+
+func (t *teeService) Fatalf(format string, args ...interface{}) {
+	for _, s := range t.services {
+		s.Fatalf(format, args...)
+	}
+}
+
+func (t *teeService) WithField(key string, value interface{}) Service {
+	next := make([]Service, len(t.services))
+	for i, s := range t.services {
+		next[i] = s.WithField(key, value)
+	}
+	return &teeService{services: next}
+}
+
+func (t *teeService) WithError(err error) Service {
+	next := make([]Service, len(t.services))
+	for i, s := range t.services {
+		next[i] = s.WithError(err)
+	}
+	return &teeService{services: next}
+}
+
+func (t *teeService) WithContext(ctx context.Context) Service {
+	next := make([]Service, len(t.services))
+	for i, s := range t.services {
+		next[i] = s.WithContext(ctx)
+	}
+	return &teeService{services: next}
+}
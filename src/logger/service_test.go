@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogrusServiceLogsFieldsErrorAndCorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	log := logrus.New()
+	log.Out = &buf
+	log.Formatter = &logrus.JSONFormatter{}
+
+	root := NewService(log)
+	withField := root.WithField("service", "hydra")
+	withErr := withField.WithError(errors.New("boom"))
+	withCtx := withErr.WithContext(ContextWithCorrelationID(context.Background(), "req-1"))
+
+	withCtx.Errorf("failed: %s", "reason")
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("unmarshal log line: %v", err)
+	}
+	if rec["msg"] != "failed: reason" {
+		t.Fatalf("msg = %v, want %q", rec["msg"], "failed: reason")
+	}
+	if rec["level"] != "error" {
+		t.Fatalf("level = %v, want %q", rec["level"], "error")
+	}
+	if rec["service"] != "hydra" {
+		t.Fatalf("service field = %v, want %q", rec["service"], "hydra")
+	}
+	if rec["error"] != "boom" {
+		t.Fatalf("error field = %v, want %q", rec["error"], "boom")
+	}
+	if rec["correlation_id"] != "req-1" {
+		t.Fatalf("correlation_id field = %v, want %q", rec["correlation_id"], "req-1")
+	}
+
+	// The chain must not mutate earlier links: logging through root (no
+	// fields/error/correlation id attached) should produce a bare entry.
+	buf.Reset()
+	root.Infof("plain")
+	rec = nil
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("unmarshal log line: %v", err)
+	}
+	for _, key := range []string{"service", "error", "correlation_id"} {
+		if _, ok := rec[key]; ok {
+			t.Fatalf("root service carried field %q from a derived call: %+v", key, rec)
+		}
+	}
+}
+
+func TestNoopServiceRecordsFields(t *testing.T) {
+	svc := NewNoopService()
+
+	svc.WithField("service", "hydra").WithField("event", "dummy_entry").Infof("hello %s", "world")
+
+	records := svc.Records()
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+
+	rec := records[0]
+	if rec.Message != "hello world" {
+		t.Fatalf("Message = %q, want %q", rec.Message, "hello world")
+	}
+	if rec.Level != "info" {
+		t.Fatalf("Level = %q, want %q", rec.Level, "info")
+	}
+	if rec.Fields["service"] != "hydra" || rec.Fields["event"] != "dummy_entry" {
+		t.Fatalf("unexpected fields: %+v", rec.Fields)
+	}
+}
+
+func TestTeeFansOutToEachService(t *testing.T) {
+	a := NewNoopService()
+	b := NewNoopService()
+
+	Tee(a, b).WithField("k", "v").Warnf("uh oh")
+
+	for _, svc := range []*NoopService{a, b} {
+		records := svc.Records()
+		if len(records) != 1 || records[0].Message != "uh oh" || records[0].Level != "warning" {
+			t.Fatalf("unexpected records for service: %+v", records)
+		}
+	}
+}
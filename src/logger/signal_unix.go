@@ -0,0 +1,40 @@
+//go:build !windows
+
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// registerReopenSignal wires name to fw.Reopen via signal.Notify. An empty
+// name defaults to "SIGHUP"; "none" disables the handler entirely.
+func registerReopenSignal(fw *reopenWriter, name string) {
+	sig, ok := parseSignalName(name)
+	if !ok {
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+	go func() {
+		for range ch {
+			if err := fw.Reopen(); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "logger: failed to reopen log file: %v\n", err)
+			}
+		}
+	}()
+}
+
+func parseSignalName(name string) (os.Signal, bool) {
+	switch name {
+	case "", "SIGHUP":
+		return syscall.SIGHUP, true
+	case "none":
+		return nil, false
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package logger
+
+// registerReopenSignal is a no-op on Windows, which has no SIGHUP
+// equivalent. Operators on Windows should restart the process to pick up a
+// rotated log file, or call logger.Reopen() from their own tooling.
+func registerReopenSignal(fw *reopenWriter, name string) {}
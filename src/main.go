@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/Caracal-IT/hydra/logger"
 )
@@ -14,15 +16,26 @@ func main() {
 	// Use empty path so Setup searches default locations instead of attempting
 	// to open a relative file that may not exist from the current working dir.
 	logger.MustSetup("")
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = logger.Close(ctx)
+	}()
 
-	// Emit a dummy log entry
-	if logger.Log != nil {
-		logger.Log.WithFields(map[string]interface{}{
-			"service": "hydra",
-			"event":   "dummy_entry",
-			"version": "0.1.0",
-		}).Info("Dummy log entry: application initialized")
-	}
+	fmt.Println(run(logger.Log))
+}
 
-	fmt.Println(Greeting())
+// run emits the startup log entry through log and returns the greeting to
+// print. It is split out from main so tests can pass a
+// logger.NewNoopService() and assert on its Records() instead of hijacking
+// os.Stdout.
+func run(log logger.Service) string {
+	if log != nil {
+		log.
+			WithField("service", "hydra").
+			WithField("event", "dummy_entry").
+			WithField("version", "0.1.0").
+			Infof("Dummy log entry: application initialized")
+	}
+	return Greeting()
 }
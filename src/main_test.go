@@ -1,10 +1,10 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
-	"os"
 	"testing"
+
+	"github.com/Caracal-IT/hydra/logger"
 )
 
 func TestGreeting(t *testing.T) {
@@ -15,41 +15,29 @@ func TestGreeting(t *testing.T) {
 		t.Fatalf("Greeting() = %q, want %q", got, want)
 	}
 
-	// Capture and validate main() output (consolidated from former main_extra_test.go)
-	orig := os.Stdout
-	r, w, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("pipe error: %v", err)
+	// Validate run()'s output and logged fields against a NoopService
+	// instead of hijacking os.Stdout.
+	noop := logger.NewNoopService()
+	if got := run(noop); got != want {
+		t.Fatalf("run() = %q, want %q", got, want)
 	}
 
-	// Ensure stdout restoration and reader closure even on failure
-	os.Stdout = w
-	defer func() {
-		// restore stdout unconditionally
-		os.Stdout = orig
-		// best-effort close reader
-		if cerr := r.Close(); cerr != nil {
-			// use t.Log rather than failing inside defer
-			t.Logf("warning: failed to close pipe reader: %v", cerr)
-		}
-	}()
-
-	// Run main which writes to stdout
-	main()
-
-	// Close writer to signal EOF to reader
-	if cerr := w.Close(); cerr != nil {
-		t.Fatalf("failed to close pipe writer: %v", cerr)
+	records := noop.Records()
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
 	}
-
-	// Read captured output
-	var buf bytes.Buffer
-	if _, err := buf.ReadFrom(r); err != nil {
-		t.Fatalf("failed to read from pipe: %v", err)
+	rec := records[0]
+	if rec.Level != "info" {
+		t.Fatalf("Level = %q, want %q", rec.Level, "info")
+	}
+	if rec.Message != "Dummy log entry: application initialized" {
+		t.Fatalf("Message = %q, want %q", rec.Message, "Dummy log entry: application initialized")
 	}
-	out := buf.String()
-	if out != "Hello from Hydra!\n" {
-		t.Fatalf("unexpected main output: %q", out)
+	wantFields := map[string]interface{}{"service": "hydra", "event": "dummy_entry", "version": "0.1.0"}
+	for k, v := range wantFields {
+		if rec.Fields[k] != v {
+			t.Fatalf("Fields[%q] = %v, want %v", k, rec.Fields[k], v)
+		}
 	}
 }
 